@@ -0,0 +1,342 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/kubeclient"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+const (
+	allRegistriesReachable = "All devfile registries are reachable"
+	emptyStatus            = "No devfile registries are listed"
+	registryUnreachable    = "Devfile registry at %s is unreachable"
+
+	manifestWorkFeedbackRule = "status.conditions[?(@.type==\"Ready\")].status"
+
+	defaultHealthCheckInterval = 5 * time.Minute
+
+	// probeBackoffBase/Max bound the requeue delay used while a registry
+	// has consecutive probe failures, separate from the steady-state
+	// Spec.HealthCheckInterval polling cadence.
+	probeBackoffBase = 10 * time.Second
+	probeBackoffMax  = 5 * time.Minute
+)
+
+// ClusterDevfileRegistriesListReconciler reconciles a ClusterDevfileRegistriesList object
+type ClusterDevfileRegistriesListReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// hasOCM records whether the OCM Placement/ManifestWork CRDs were
+	// detected on the hub cluster at startup. When false, placement-based
+	// fan out is skipped entirely and the controller behaves exactly as it
+	// did before that feature existed.
+	hasOCM bool
+}
+
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=clusterdevfileregistrieslists,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=clusterdevfileregistrieslists/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=placements;placementdecisions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=work.open-cluster-management.io,resources=manifestworks,verbs=get;list;watch;create;update;patch;delete
+
+func (r *ClusterDevfileRegistriesListReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("clusterdevfileregistrieslist", req.NamespacedName)
+
+	drl := &registryv1alpha1.ClusterDevfileRegistriesList{}
+	if err := r.Get(ctx, req.NamespacedName, drl); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("ClusterDevfileRegistriesList resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get ClusterDevfileRegistriesList")
+		return ctrl.Result{}, err
+	}
+
+	interval := defaultHealthCheckInterval
+	if drl.Spec.HealthCheckInterval != nil {
+		interval = drl.Spec.HealthCheckInterval.Duration
+	}
+
+	maxConsecutiveFailures := r.probeRegistries(drl)
+	message, registryStatuses := drl.Status.Message, drl.Status.RegistryStatuses
+
+	key := client.ObjectKeyFromObject(drl)
+	if err := kubeclient.UpdateStatusWithRetry(ctx, r.Client, drl, func() error {
+		if err := r.Get(ctx, key, drl); err != nil {
+			return err
+		}
+		drl.Status.Message = message
+		drl.Status.RegistryStatuses = registryStatuses
+		return nil
+	}); err != nil {
+		log.Error(err, "Failed to update ClusterDevfileRegistriesList status")
+		return ctrl.Result{}, err
+	}
+
+	if r.hasOCM && drl.Spec.Placement != nil {
+		if err := r.propagateToManagedClusters(ctx, drl); err != nil {
+			log.Error(err, "Failed to propagate registries list to managed clusters")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if maxConsecutiveFailures > 0 {
+		return ctrl.Result{RequeueAfter: util.Backoff(maxConsecutiveFailures, probeBackoffBase, probeBackoffMax)}, nil
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// probeRegistries polls every registry in drl.Spec.DevfileRegistries with
+// the shared util.Probe subsystem, updates drl.Status in place with the
+// per-URL results, emits an Event on every reachable/unreachable
+// transition, and returns the highest ConsecutiveFailures count observed
+// across all registries (0 when all are reachable).
+func (r *ClusterDevfileRegistriesListReconciler) probeRegistries(drl *registryv1alpha1.ClusterDevfileRegistriesList) int {
+	if len(drl.Spec.DevfileRegistries) == 0 {
+		drl.Status.Message = emptyStatus
+		drl.Status.RegistryStatuses = nil
+		return 0
+	}
+
+	statuses, message, maxConsecutiveFailures := probeRegistryStatuses(drl.Spec.DevfileRegistries, drl.Status.RegistryStatuses, r.Recorder, drl)
+	drl.Status.Message = message
+	drl.Status.RegistryStatuses = statuses
+	return maxConsecutiveFailures
+}
+
+// propagateToManagedClusters resolves the PlacementDecisions for
+// drl.Spec.Placement and ensures one ManifestWork per selected managed
+// cluster, carrying a namespaced DevfileRegistriesList rendered from the
+// reachable subset of drl.Spec.DevfileRegistries plus a ConfigMap mirror of
+// the same data. Status.ManagedClusters is updated with, per cluster,
+// whether the manifest applied and whether the remote registry-operator
+// reported the child CR healthy.
+func (r *ClusterDevfileRegistriesListReconciler) propagateToManagedClusters(ctx context.Context, drl *registryv1alpha1.ClusterDevfileRegistriesList) error {
+	decisions, err := r.placementDecisionsFor(ctx, drl.Namespace, drl.Spec.Placement.Name)
+	if err != nil {
+		return err
+	}
+
+	reachable := reachableRegistries(drl.Spec.DevfileRegistries, drl.Status.RegistryStatuses)
+
+	clusterStatuses := make([]registryv1alpha1.ManagedClusterStatus, 0, len(decisions))
+	for _, clusterName := range selectedClusters(decisions) {
+		manifestWork, err := r.desiredManifestWork(drl, clusterName, reachable)
+		if err != nil {
+			return err
+		}
+
+		existing := &workv1.ManifestWork{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      manifestWork.Name,
+				Namespace: manifestWork.Namespace,
+			},
+		}
+		if _, err := kubeclient.CreateOrPatchWithRetry(ctx, r.Client, existing, func() error {
+			existing.Spec = manifestWork.Spec
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		clusterStatuses = append(clusterStatuses, registryv1alpha1.ManagedClusterStatus{
+			ClusterName: clusterName,
+			Applied:     manifestWorkApplied(existing),
+			Healthy:     manifestWorkReportsHealthy(existing),
+		})
+	}
+
+	key := client.ObjectKeyFromObject(drl)
+	return kubeclient.UpdateStatusWithRetry(ctx, r.Client, drl, func() error {
+		if err := r.Get(ctx, key, drl); err != nil {
+			return err
+		}
+		drl.Status.ManagedClusters = clusterStatuses
+		return nil
+	})
+}
+
+func (r *ClusterDevfileRegistriesListReconciler) placementDecisionsFor(ctx context.Context, namespace, placementName string) ([]clusterv1beta1.PlacementDecision, error) {
+	decisionList := &clusterv1beta1.PlacementDecisionList{}
+	if err := r.List(ctx, decisionList, client.InNamespace(namespace), client.MatchingLabels{
+		"cluster.open-cluster-management.io/placement": placementName,
+	}); err != nil {
+		return nil, err
+	}
+	return decisionList.Items, nil
+}
+
+func selectedClusters(decisions []clusterv1beta1.PlacementDecision) []string {
+	var clusters []string
+	for _, decision := range decisions {
+		for _, d := range decision.Status.Decisions {
+			clusters = append(clusters, d.ClusterName)
+		}
+	}
+	return clusters
+}
+
+// reachableRegistries filters all down to the subset whose most recent
+// probe (run by probeRegistries via the shared util.Probe subsystem) came
+// back reachable, so propagation doesn't re-probe the same URLs a second
+// time with different semantics.
+func reachableRegistries(all []registryv1alpha1.DevfileRegistryService, statuses []registryv1alpha1.RegistryProbeStatus) []registryv1alpha1.DevfileRegistryService {
+	reachableURLs := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		reachableURLs[s.URL] = s.Reachable
+	}
+
+	reachable := make([]registryv1alpha1.DevfileRegistryService, 0, len(all))
+	for _, reg := range all {
+		if reachableURLs[reg.URL] {
+			reachable = append(reachable, reg)
+		}
+	}
+	return reachable
+}
+
+func (r *ClusterDevfileRegistriesListReconciler) desiredManifestWork(drl *registryv1alpha1.ClusterDevfileRegistriesList, clusterName string, reachable []registryv1alpha1.DevfileRegistryService) (*workv1.ManifestWork, error) {
+	namespacedList := &registryv1alpha1.DevfileRegistriesList{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: registryv1alpha1.GroupVersion.String(),
+			Kind:       "DevfileRegistriesList",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      drl.Name,
+			Namespace: drl.Namespace,
+		},
+		Spec: registryv1alpha1.DevfileRegistriesListSpec{
+			DevfileRegistries: reachable,
+		},
+	}
+
+	rawList, err := json.Marshal(namespacedList)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      drl.Name,
+			Namespace: drl.Namespace,
+		},
+		Data: map[string]string{},
+	}
+	for _, reg := range reachable {
+		configMap.Data[reg.Name] = reg.URL
+	}
+	rawConfigMap, err := json.Marshal(configMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      drl.Name,
+			Namespace: clusterName,
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{
+					{RawExtension: runtime.RawExtension{Raw: rawList}},
+					{RawExtension: runtime.RawExtension{Raw: rawConfigMap}},
+				},
+			},
+			ManifestConfigs: []workv1.ManifestConfigOption{
+				{
+					ResourceIdentifier: workv1.ResourceIdentifier{
+						Group:     registryv1alpha1.GroupVersion.Group,
+						Resource:  "devfileregistrieslists",
+						Name:      drl.Name,
+						Namespace: drl.Namespace,
+					},
+					FeedbackRules: []workv1.FeedbackRule{
+						{Type: workv1.JSONPathsType, JsonPaths: []workv1.JsonPath{{Name: "ready", Path: manifestWorkFeedbackRule}}},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func manifestWorkApplied(mw *workv1.ManifestWork) bool {
+	for _, cond := range mw.Status.Conditions {
+		if cond.Type == workv1.WorkApplied && cond.Status == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+func manifestWorkReportsHealthy(mw *workv1.ManifestWork) bool {
+	for _, status := range mw.Status.ResourceStatus.Manifests {
+		for _, value := range status.StatusFeedbacks.Values {
+			if value.Name == "ready" && value.Value.String != nil && *value.Value.String == "True" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *ClusterDevfileRegistriesListReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.hasOCM = ocmCRDsPresent(mgr)
+	r.Recorder = mgr.GetEventRecorderFor("clusterdevfileregistrieslist-controller")
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&registryv1alpha1.ClusterDevfileRegistriesList{})
+
+	if r.hasOCM {
+		builder = builder.Owns(&workv1.ManifestWork{})
+	}
+
+	return builder.Complete(r)
+}
+
+// ocmCRDsPresent probes the cluster for the Open Cluster Management
+// Placement/ManifestWork APIs, mirroring the way cluster.IsOpenShift()
+// detects OpenShift-only APIs, so the controller degrades gracefully on
+// clusters where OCM isn't installed.
+func ocmCRDsPresent(mgr ctrl.Manager) bool {
+	restMapper := mgr.GetRESTMapper()
+	_, workErr := restMapper.RESTMapping(workv1.GroupVersion.WithKind("ManifestWork").GroupKind())
+	_, placementErr := restMapper.RESTMapping(clusterv1beta1.GroupVersion.WithKind("PlacementDecision").GroupKind())
+	return workErr == nil && placementErr == nil
+}
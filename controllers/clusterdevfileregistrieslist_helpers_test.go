@@ -0,0 +1,171 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	workv1 "open-cluster-management.io/api/work/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestManifestWorkApplied(t *testing.T) {
+	tests := []struct {
+		name string
+		mw   *workv1.ManifestWork
+		want bool
+	}{
+		{name: "no conditions", mw: &workv1.ManifestWork{}, want: false},
+		{
+			name: "applied",
+			mw: &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue}},
+			}},
+			want: true,
+		},
+		{
+			name: "not yet applied",
+			mw: &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{
+				Conditions: []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionFalse}},
+			}},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifestWorkApplied(tt.mw); got != tt.want {
+				t.Errorf("manifestWorkApplied() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestWorkReportsHealthy(t *testing.T) {
+	healthyFeedback := workv1.ManifestWork{Status: workv1.ManifestWorkStatus{
+		ResourceStatus: workv1.ManifestResourceStatus{
+			Manifests: []workv1.ManifestCondition{{
+				StatusFeedbacks: workv1.StatusFeedbackResult{
+					Values: []workv1.FeedbackValue{{Name: "ready", Value: workv1.FieldValue{String: strPtr("True")}}},
+				},
+			}},
+		},
+	}}
+
+	unhealthyFeedback := workv1.ManifestWork{Status: workv1.ManifestWorkStatus{
+		ResourceStatus: workv1.ManifestResourceStatus{
+			Manifests: []workv1.ManifestCondition{{
+				StatusFeedbacks: workv1.StatusFeedbackResult{
+					Values: []workv1.FeedbackValue{{Name: "ready", Value: workv1.FieldValue{String: strPtr("False")}}},
+				},
+			}},
+		},
+	}}
+
+	tests := []struct {
+		name string
+		mw   *workv1.ManifestWork
+		want bool
+	}{
+		{name: "no feedback yet", mw: &workv1.ManifestWork{}, want: false},
+		{name: "ready=True", mw: &healthyFeedback, want: true},
+		{name: "ready=False", mw: &unhealthyFeedback, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := manifestWorkReportsHealthy(tt.mw); got != tt.want {
+				t.Errorf("manifestWorkReportsHealthy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectedClusters(t *testing.T) {
+	decisions := []clusterv1beta1.PlacementDecision{
+		{Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{
+			{ClusterName: "cluster-a"}, {ClusterName: "cluster-b"},
+		}}},
+		{Status: clusterv1beta1.PlacementDecisionStatus{Decisions: []clusterv1beta1.ClusterDecision{
+			{ClusterName: "cluster-c"},
+		}}},
+	}
+
+	got := selectedClusters(decisions)
+	want := []string{"cluster-a", "cluster-b", "cluster-c"}
+	if len(got) != len(want) {
+		t.Fatalf("selectedClusters() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("selectedClusters()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReachableRegistries(t *testing.T) {
+	all := []registryv1alpha1.DevfileRegistryService{
+		{Name: "up", URL: "http://up.example.com"},
+		{Name: "down", URL: "http://down.example.com"},
+		{Name: "unprobed", URL: "http://unprobed.example.com"},
+	}
+	statuses := []registryv1alpha1.RegistryProbeStatus{
+		{URL: "http://up.example.com", Reachable: true},
+		{URL: "http://down.example.com", Reachable: false},
+	}
+
+	got := reachableRegistries(all, statuses)
+	if len(got) != 1 || got[0].Name != "up" {
+		t.Errorf("reachableRegistries() = %+v, want only the %q registry", got, "up")
+	}
+}
+
+func TestClusterDevfileRegistriesListReconcileWithoutOCM(t *testing.T) {
+	scheme := newDevfileRegistriesListScheme(t)
+
+	drl := &registryv1alpha1.ClusterDevfileRegistriesList{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample"},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(drl).WithStatusSubresource(drl).Build()
+
+	r := &ClusterDevfileRegistriesListReconciler{Client: c, Log: ctrl.Log.WithName("test"), Scheme: scheme}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drl)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != defaultHealthCheckInterval {
+		t.Errorf("RequeueAfter = %s, want the default health check interval %s", result.RequeueAfter, defaultHealthCheckInterval)
+	}
+
+	updated := &registryv1alpha1.ClusterDevfileRegistriesList{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(drl), updated); err != nil {
+		t.Fatalf("failed to get updated ClusterDevfileRegistriesList: %v", err)
+	}
+	if updated.Status.Message != emptyStatus {
+		t.Errorf("Status.Message = %q, want %q", updated.Status.Message, emptyStatus)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/kubeclient"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+// DevfileRegistriesListReconciler reconciles a DevfileRegistriesList object.
+// It is the namespaced counterpart of ClusterDevfileRegistriesListReconciler:
+// same periodic reachability polling, but no OCM fan out, since a
+// DevfileRegistriesList is itself the kind of object the hub renders onto a
+// managed cluster via ManifestWork. Its rolled-up Ready condition is what
+// ManifestWork.Status.ResourceStatus feedback rules on the hub read back to
+// tell whether that managed cluster's copy is healthy.
+type DevfileRegistriesListReconciler struct {
+	client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistrieslists,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistrieslists/status,verbs=get;update;patch
+
+func (r *DevfileRegistriesListReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("devfileregistrieslist", req.NamespacedName)
+
+	drl := &registryv1alpha1.DevfileRegistriesList{}
+	if err := r.Get(ctx, req.NamespacedName, drl); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("DevfileRegistriesList resource not found. Ignoring since object must be deleted")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get DevfileRegistriesList")
+		return ctrl.Result{}, err
+	}
+
+	interval := defaultHealthCheckInterval
+	if drl.Spec.HealthCheckInterval != nil {
+		interval = drl.Spec.HealthCheckInterval.Duration
+	}
+
+	maxConsecutiveFailures := r.probeRegistries(drl)
+	message, registryStatuses, conditions := drl.Status.Message, drl.Status.RegistryStatuses, drl.Status.Conditions
+
+	key := client.ObjectKeyFromObject(drl)
+	if err := kubeclient.UpdateStatusWithRetry(ctx, r.Client, drl, func() error {
+		if err := r.Get(ctx, key, drl); err != nil {
+			return err
+		}
+		drl.Status.Message = message
+		drl.Status.RegistryStatuses = registryStatuses
+		drl.Status.Conditions = conditions
+		return nil
+	}); err != nil {
+		log.Error(err, "Failed to update DevfileRegistriesList status")
+		return ctrl.Result{}, err
+	}
+
+	if maxConsecutiveFailures > 0 {
+		return ctrl.Result{RequeueAfter: util.Backoff(maxConsecutiveFailures, probeBackoffBase, probeBackoffMax)}, nil
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// probeRegistries polls every registry in drl.Spec.DevfileRegistries with
+// util.Probe, updates drl.Status (including the rolled-up Ready condition)
+// in place, emits an Event on every reachable/unreachable transition, and
+// returns the highest ConsecutiveFailures count observed (0 when all are
+// reachable).
+func (r *DevfileRegistriesListReconciler) probeRegistries(drl *registryv1alpha1.DevfileRegistriesList) int {
+	if len(drl.Spec.DevfileRegistries) == 0 {
+		drl.Status.Message = emptyStatus
+		drl.Status.RegistryStatuses = nil
+		meta.SetStatusCondition(&drl.Status.Conditions, readyCondition(true, "NoRegistries", emptyStatus))
+		return 0
+	}
+
+	statuses, message, maxConsecutiveFailures := probeRegistryStatuses(drl.Spec.DevfileRegistries, drl.Status.RegistryStatuses, r.Recorder, drl)
+	drl.Status.Message = message
+	drl.Status.RegistryStatuses = statuses
+
+	allReachable := true
+	for _, s := range statuses {
+		if !s.Reachable {
+			allReachable = false
+			break
+		}
+	}
+	if allReachable {
+		meta.SetStatusCondition(&drl.Status.Conditions, readyCondition(true, "AllRegistriesReachable", message))
+	} else {
+		meta.SetStatusCondition(&drl.Status.Conditions, readyCondition(false, "RegistryUnreachable", message))
+	}
+	return maxConsecutiveFailures
+}
+
+func readyCondition(ready bool, reason, message string) metav1.Condition {
+	status := metav1.ConditionFalse
+	if ready {
+		status = metav1.ConditionTrue
+	}
+	return metav1.Condition{
+		Type:    "Ready",
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func (r *DevfileRegistriesListReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor("devfileregistrieslist-controller")
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&registryv1alpha1.DevfileRegistriesList{}).
+		Complete(r)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+func findCondition(conditions []metav1.Condition, condType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func newDevfileRegistriesListScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := registryv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	return scheme
+}
+
+func TestDevfileRegistriesListReconcile(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+
+	drl := &registryv1alpha1.DevfileRegistriesList{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"},
+		Spec: registryv1alpha1.DevfileRegistriesListSpec{
+			DevfileRegistries: []registryv1alpha1.DevfileRegistryService{{Name: "staging", URL: up.URL}},
+		},
+	}
+
+	scheme := newDevfileRegistriesListScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(drl).WithStatusSubresource(drl).Build()
+
+	r := &DevfileRegistriesListReconciler{
+		Client:   c,
+		Log:      ctrl.Log.WithName("test"),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drl)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != defaultHealthCheckInterval {
+		t.Errorf("RequeueAfter = %s, want the default health check interval %s", result.RequeueAfter, defaultHealthCheckInterval)
+	}
+
+	updated := &registryv1alpha1.DevfileRegistriesList{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(drl), updated); err != nil {
+		t.Fatalf("failed to get updated DevfileRegistriesList: %v", err)
+	}
+	if len(updated.Status.RegistryStatuses) != 1 || !updated.Status.RegistryStatuses[0].Reachable {
+		t.Errorf("Status.RegistryStatuses = %+v, want a single reachable entry", updated.Status.RegistryStatuses)
+	}
+
+	ready := findCondition(updated.Status.Conditions, "Ready")
+	if ready == nil {
+		t.Fatal("expected a Ready condition to be set")
+	}
+	if ready.Status != metav1.ConditionTrue {
+		t.Errorf("Ready condition status = %v, want True", ready.Status)
+	}
+}
+
+func TestDevfileRegistriesListReconcileCustomInterval(t *testing.T) {
+	drl := &registryv1alpha1.DevfileRegistriesList{
+		ObjectMeta: metav1.ObjectMeta{Name: "sample", Namespace: "default"},
+		Spec: registryv1alpha1.DevfileRegistriesListSpec{
+			HealthCheckInterval: &metav1.Duration{Duration: 1 * time.Minute},
+		},
+	}
+
+	scheme := newDevfileRegistriesListScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(drl).WithStatusSubresource(drl).Build()
+
+	r := &DevfileRegistriesListReconciler{Client: c, Log: ctrl.Log.WithName("test"), Scheme: scheme}
+
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(drl)})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != 1*time.Minute {
+		t.Errorf("RequeueAfter = %s, want Spec.HealthCheckInterval (1m)", result.RequeueAfter)
+	}
+}
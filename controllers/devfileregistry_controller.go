@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -26,15 +28,24 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
 	"github.com/devfile/registry-operator/pkg/cluster"
 	"github.com/devfile/registry-operator/pkg/config"
+	"github.com/devfile/registry-operator/pkg/kubeclient"
 	"github.com/devfile/registry-operator/pkg/registry"
+	"github.com/devfile/registry-operator/pkg/statuscheck"
 	"github.com/devfile/registry-operator/pkg/util"
 )
 
@@ -43,6 +54,32 @@ type DevfileRegistryReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+
+	// notReadyStreaks tracks, per DevfileRegistry, how many consecutive
+	// reconciles have observed at least one not-ready owned resource or a
+	// failed HTTP probe of the registry server, so that requeues can back
+	// off exponentially instead of busy-looping.
+	notReadyStreaksMu sync.Mutex
+	notReadyStreaks   map[types.NamespacedName]int
+}
+
+const (
+	readinessRequeueBase = 2 * time.Second
+	readinessRequeueMax  = 2 * time.Minute
+)
+
+// readinessBackoff returns the requeue delay for the nth (1-indexed)
+// consecutive not-ready reconcile of a DevfileRegistry, doubling from
+// readinessRequeueBase up to a readinessRequeueMax ceiling.
+func readinessBackoff(streak int) time.Duration {
+	d := readinessRequeueBase
+	for i := 1; i < streak; i++ {
+		d *= 2
+		if d >= readinessRequeueMax {
+			return readinessRequeueMax
+		}
+	}
+	return d
 }
 
 // +kubebuilder:rbac:groups=registry.devfile.io,resources=devfileregistries,verbs=get;list;watch;create;update;patch;delete
@@ -145,20 +182,68 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		devfileRegistryServer = "http://" + hostname
 	}
 
+	// Aggregate the readiness of every owned resource into conditions before
+	// trusting the registry is actually serving traffic.
+	nsName := req.NamespacedName
+	resourcesReady, conditions, resourceStatuses, err := r.checkResourcesReady(ctx, devfileRegistry, hostname)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// The resource was just created by ensure() above, but the
+			// controller's cached client likely hasn't observed it yet. See
+			// https://github.com/operator-framework/operator-sdk/issues/4013#issuecomment-707267616
+			// for why we requeue rather than error out here.
+			log.Info("An owned resource isn't in the cache yet, requeueing", "error", err)
+			return ctrl.Result{Requeue: true}, nil
+		}
+		log.Error(err, "Failed to aggregate owned resource status")
+		return ctrl.Result{}, err
+	}
+
+	applyAggregatedStatus := func(dr *registryv1alpha1.DevfileRegistry) {
+		for _, cond := range conditions {
+			meta.SetStatusCondition(&dr.Status.Conditions, cond)
+		}
+		dr.Status.Resources = resourceStatuses
+	}
+
+	if !resourcesReady {
+		streak := r.recordNotReady(nsName)
+		if err := r.updateStatusWithRetry(ctx, devfileRegistry, applyAggregatedStatus); err != nil {
+			log.Error(err, "Failed to update DevfileRegistry status")
+			return ctrl.Result{}, err
+		}
+		backoff := readinessBackoff(streak)
+		log.Info("Owned resources not ready yet, requeueing", "backoff", backoff)
+		return ctrl.Result{RequeueAfter: backoff}, nil
+	}
+	r.clearNotReady(nsName)
+
 	if devfileRegistry.Status.URL != devfileRegistryServer {
 		// Check to see if the registry is active, and if so, update the status to reflect the URL
 		// when deploying a new devfile registry, it may not have a signed cert installed yet, so we will skip TLS checking.  We just want to make sure
 		// server is up and running
-		err = util.WaitForServer(devfileRegistryServer, 30*time.Second, false)
-		if err != nil {
-			log.Error(err, "Devfile registry server failed to start after 30 seconds, re-queueing...")
-			return ctrl.Result{Requeue: true}, err
+		probeResult := util.Probe(devfileRegistryServer, util.ProbeOptions{Timeout: 30 * time.Second})
+		if !probeResult.Reachable {
+			message := fmt.Sprintf("unexpected status code %d", probeResult.StatusCode)
+			if probeResult.Err != nil {
+				message = probeResult.Err.Error()
+			}
+			log.Info("Devfile registry server failed to start, re-queueing...", "reason", message)
+			if statusErr := r.updateStatusWithRetry(ctx, devfileRegistry, func(dr *registryv1alpha1.DevfileRegistry) {
+				applyAggregatedStatus(dr)
+				meta.SetStatusCondition(&dr.Status.Conditions, readyConditionNotYet("HTTPProbeFailed", message))
+			}); statusErr != nil {
+				log.Error(statusErr, "Failed to update DevfileRegistry status")
+			}
+			streak := r.recordNotReady(nsName)
+			return ctrl.Result{RequeueAfter: readinessBackoff(streak)}, nil
 		}
 
 		// Update the status
-		devfileRegistry.Status.URL = devfileRegistryServer
-		err := r.Status().Update(ctx, devfileRegistry)
-		if err != nil {
+		if err := r.updateStatusWithRetry(ctx, devfileRegistry, func(dr *registryv1alpha1.DevfileRegistry) {
+			applyAggregatedStatus(dr)
+			dr.Status.URL = devfileRegistryServer
+		}); err != nil {
 			log.Error(err, "Failed to update DevfileRegistry status")
 			return ctrl.Result{Requeue: true}, err
 		}
@@ -169,11 +254,266 @@ func (r *DevfileRegistryReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			return *result, err
 		}
 
+	} else if err := r.updateStatusWithRetry(ctx, devfileRegistry, applyAggregatedStatus); err != nil {
+		log.Error(err, "Failed to update DevfileRegistry status")
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// checkResourcesReady inspects every resource owned by devfileRegistry and
+// returns whether they are all ready, the conditions to record on
+// DevfileRegistry.Status.Conditions, and a Status.Resources snapshot
+// enumerating each owned object so `kubectl get devfileregistry -o yaml`
+// shows the whole deployment's health in one place.
+func (r *DevfileRegistryReconciler) checkResourcesReady(ctx context.Context, devfileRegistry *registryv1alpha1.DevfileRegistry, hostname string) (bool, []metav1.Condition, []registryv1alpha1.ResourceStatus, error) {
+	results := map[string]statuscheck.Result{}
+	var resources []registryv1alpha1.ResourceStatus
+
+	deployment := &appsv1.Deployment{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.DeploymentName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, deployment); err != nil {
+		return false, nil, nil, err
+	}
+	depResult, err := statuscheck.CheckDeployment(ctx, r.Client, deployment)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	results[statuscheck.ConditionDeploymentAvailable] = depResult
+	resources = append(resources, r.resourceStatusFor(deployment, depResult))
+
+	svc := &corev1.Service{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.ServiceName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, svc); err != nil {
+		return false, nil, nil, err
+	}
+	svcResult := statuscheck.CheckService(svc)
+	results[statuscheck.ConditionServiceReady] = svcResult
+	resources = append(resources, r.resourceStatusFor(svc, svcResult))
+
+	configMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: registry.ConfigMapName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, configMap); err != nil {
+		return false, nil, nil, err
+	}
+	resources = append(resources, r.resourceStatusFor(configMap, statuscheck.Result{Ready: true, Reason: "Exists"}))
+
+	if registry.IsStorageEnabled(devfileRegistry) {
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := r.Get(ctx, types.NamespacedName{Name: registry.PVCName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, pvc); err != nil {
+			return false, nil, nil, err
+		}
+		pvcResult := statuscheck.CheckPVC(pvc)
+		results[statuscheck.ConditionStorageBound] = pvcResult
+		resources = append(resources, r.resourceStatusFor(pvc, pvcResult))
+	}
+
+	if config.ControllerCfg.IsOpenShift() && devfileRegistry.Spec.K8s.IngressDomain == "" {
+		route := &routev1.Route{}
+		if err := r.Get(ctx, types.NamespacedName{Name: registry.IngressName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, route); err != nil {
+			return false, nil, nil, err
+		}
+		routeResult := statuscheck.CheckRoute(route)
+		results[statuscheck.ConditionRouteAdmitted] = routeResult
+		resources = append(resources, r.resourceStatusFor(route, routeResult))
+	} else {
+		ingress := &networkingv1.Ingress{}
+		if err := r.Get(ctx, types.NamespacedName{Name: registry.IngressName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}, ingress); err != nil {
+			return false, nil, nil, err
+		}
+		ingressResult := statuscheck.CheckIngress(ingress)
+		results[statuscheck.ConditionIngressAdmitted] = ingressResult
+		resources = append(resources, r.resourceStatusFor(ingress, ingressResult))
+	}
+
+	conditions := statuscheck.Aggregate(devfileRegistry.Generation, results)
+
+	allReady := true
+	for _, res := range results {
+		if !res.Ready {
+			allReady = false
+			break
+		}
+	}
+	return allReady, conditions, resources, nil
+}
+
+// resourceStatusFor builds the Status.Resources entry for a single owned
+// object, using its GVK (resolved via the reconciler's scheme, since the
+// typed client doesn't stamp TypeMeta on Get) and the already-computed
+// statuscheck.Result for that object.
+func (r *DevfileRegistryReconciler) resourceStatusFor(obj client.Object, result statuscheck.Result) registryv1alpha1.ResourceStatus {
+	gvk, err := apiutil.GVKForObject(obj, r.Scheme)
+	if err != nil {
+		r.Log.Error(err, "Failed to resolve GVK for owned resource", "name", obj.GetName())
+	}
+	return registryv1alpha1.ResourceStatus{
+		Group:              gvk.Group,
+		Version:            gvk.Version,
+		Kind:               gvk.Kind,
+		Name:               obj.GetName(),
+		Namespace:          obj.GetNamespace(),
+		ObservedGeneration: obj.GetGeneration(),
+		Ready:              result.Ready,
+		Reason:             result.Reason,
+		Message:            result.Message,
+	}
+}
+
+// ensure makes obj (an empty, typed template such as &corev1.Service{})
+// match the desired state for devfileRegistry, creating it if it doesn't
+// exist yet. It returns a non-nil *ctrl.Result only when the caller should
+// stop reconciling and return that result directly; a nil result with a
+// nil error means the resource is up to date and reconciliation should
+// continue.
+func (r *DevfileRegistryReconciler) ensure(ctx context.Context, devfileRegistry *registryv1alpha1.DevfileRegistry, obj client.Object, labels map[string]string, hostname string) (*ctrl.Result, error) {
+	name, mutate, err := r.mutatorFor(devfileRegistry, obj, labels, hostname)
+	if err != nil {
+		return &ctrl.Result{}, err
+	}
+	obj.SetName(name)
+	obj.SetNamespace(devfileRegistry.Namespace)
+
+	opResult, err := kubeclient.CreateOrPatchWithRetry(ctx, r.Client, obj, func() error {
+		if err := ctrl.SetControllerReference(devfileRegistry, obj, r.Scheme); err != nil {
+			return err
+		}
+		return mutate()
+	})
+	if err != nil {
+		// CreateOrPatchWithRetry already exhausted its own bounded retries
+		// on Conflict/Transient errors, so seeing one here means the race
+		// is still in progress -- requeue and try again on the next pass
+		// rather than treating it as a fatal reconcile error.
+		switch opResult {
+		case kubeclient.Conflict, kubeclient.Transient:
+			r.Log.Info("Transient error ensuring resource, requeueing", "kind", fmt.Sprintf("%T", obj), "name", name, "reason", err)
+			return &ctrl.Result{Requeue: true}, nil
+		default:
+			r.Log.Error(err, "Failed to ensure resource", "kind", fmt.Sprintf("%T", obj), "name", name)
+			return &ctrl.Result{}, err
+		}
+	}
+	if opResult == kubeclient.Created {
+		r.Log.Info("Created resource", "kind", fmt.Sprintf("%T", obj), "name", name)
+	}
+	return nil, nil
+}
+
+// mutatorFor returns the name the resource should have and the mutate
+// function CreateOrPatchWithRetry should run against it, dispatching on
+// obj's concrete type since each owned resource is built by its own
+// pkg/registry generator.
+func (r *DevfileRegistryReconciler) mutatorFor(devfileRegistry *registryv1alpha1.DevfileRegistry, obj client.Object, labels map[string]string, hostname string) (string, func() error, error) {
+	switch o := obj.(type) {
+	case *corev1.Service:
+		return registry.ServiceName(devfileRegistry.Name), func() error {
+			return registry.MutateService(o, devfileRegistry, labels)
+		}, nil
+	case *corev1.PersistentVolumeClaim:
+		return registry.PVCName(devfileRegistry.Name), func() error {
+			return registry.MutatePVC(o, devfileRegistry, labels)
+		}, nil
+	case *corev1.ConfigMap:
+		return registry.ConfigMapName(devfileRegistry.Name), func() error {
+			return registry.MutateConfigMap(o, devfileRegistry, labels)
+		}, nil
+	case *appsv1.Deployment:
+		return registry.DeploymentName(devfileRegistry.Name), func() error {
+			return registry.MutateDeployment(o, devfileRegistry, labels)
+		}, nil
+	case *routev1.Route:
+		return registry.IngressName(devfileRegistry.Name), func() error {
+			return registry.MutateRoute(o, devfileRegistry, labels)
+		}, nil
+	case *networkingv1.Ingress:
+		return registry.IngressName(devfileRegistry.Name), func() error {
+			return registry.MutateIngress(o, devfileRegistry, labels, hostname)
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("ensure: unsupported resource type %T", obj)
+	}
+}
+
+// deleteOldPVCIfNeeded removes the devfile registry's PersistentVolumeClaim
+// once storage has been disabled on the spec. It has to run after the
+// Deployment has already been updated to stop mounting the PVC, otherwise
+// the delete would race the kubelet unmounting it from the old pod.
+func (r *DevfileRegistryReconciler) deleteOldPVCIfNeeded(ctx context.Context, devfileRegistry *registryv1alpha1.DevfileRegistry) error {
+	if registry.IsStorageEnabled(devfileRegistry) {
+		return nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	key := types.NamespacedName{Name: registry.PVCName(devfileRegistry.Name), Namespace: devfileRegistry.Namespace}
+	if err := kubeclient.GetWithRetry(ctx, r.Client, key, pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	r.Log.Info("Storage disabled, deleting orphaned PersistentVolumeClaim", "name", pvc.Name)
+	return kubeclient.DeleteWithRetry(ctx, r.Client, pvc)
+}
+
+func readyConditionNotYet(reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    statuscheck.ConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// updateStatusWithRetry re-fetches devfileRegistry and re-applies apply to
+// it before every attempt, so a conflict from another writer (e.g. a
+// concurrent reconcile of the same object) is retried against the latest
+// resourceVersion instead of failing the whole reconcile.
+func (r *DevfileRegistryReconciler) updateStatusWithRetry(ctx context.Context, devfileRegistry *registryv1alpha1.DevfileRegistry, apply func(*registryv1alpha1.DevfileRegistry)) error {
+	key := client.ObjectKeyFromObject(devfileRegistry)
+	return kubeclient.UpdateStatusWithRetry(ctx, r.Client, devfileRegistry, func() error {
+		if err := r.Get(ctx, key, devfileRegistry); err != nil {
+			return err
+		}
+		apply(devfileRegistry)
+		return nil
+	})
+}
+
+func (r *DevfileRegistryReconciler) recordNotReady(name types.NamespacedName) int {
+	r.notReadyStreaksMu.Lock()
+	defer r.notReadyStreaksMu.Unlock()
+	if r.notReadyStreaks == nil {
+		r.notReadyStreaks = map[types.NamespacedName]int{}
+	}
+	r.notReadyStreaks[name]++
+	return r.notReadyStreaks[name]
+}
+
+func (r *DevfileRegistryReconciler) clearNotReady(name types.NamespacedName) {
+	r.notReadyStreaksMu.Lock()
+	defer r.notReadyStreaksMu.Unlock()
+	delete(r.notReadyStreaks, name)
+}
+
+// statusOnlyUpdatePredicate enqueues a reconcile for an owned resource's
+// Update events only when its status actually changed (resourceVersion
+// moved but generation didn't) -- generation bumps come from spec edits we
+// ourselves made via ensure(), which already trigger a reconcile through
+// the owning DevfileRegistry and would otherwise cause duplicate churn.
+func statusOnlyUpdatePredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return true },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
+		GenericFunc: func(e event.GenericEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+				return false
+			}
+			return e.ObjectOld.GetResourceVersion() != e.ObjectNew.GetResourceVersion()
+		},
+	}
+}
+
 func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Check if we're running on OpenShift
 	isOS, err := cluster.IsOpenShift()
@@ -182,19 +522,21 @@ func (r *DevfileRegistryReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	}
 	config.ControllerCfg.SetIsOpenShift(isOS)
 
-	builder := ctrl.NewControllerManagedBy(mgr).
+	owner := handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &registryv1alpha1.DevfileRegistry{})
+	withStatusPredicate := builder.WithPredicates(statusOnlyUpdatePredicate())
+
+	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&registryv1alpha1.DevfileRegistry{}).
-		Owns(&appsv1.Deployment{}).
-		Owns(&corev1.Service{}).
-		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&networkingv1.Ingress{}).
-		Owns(&corev1.ConfigMap{})
+		Watches(&appsv1.Deployment{}, owner, withStatusPredicate).
+		Watches(&corev1.Service{}, owner, withStatusPredicate).
+		Watches(&corev1.PersistentVolumeClaim{}, owner, withStatusPredicate).
+		Watches(&networkingv1.Ingress{}, owner, withStatusPredicate).
+		Watches(&corev1.ConfigMap{}, owner, withStatusPredicate)
 
 	// If on OpenShift, mark routes as owned by the controller
 	if config.ControllerCfg.IsOpenShift() {
-		builder.Owns(&routev1.Route{})
+		ctrlBuilder.Watches(&routev1.Route{}, owner, withStatusPredicate)
 	}
 
-	return builder.Complete(r)
-
+	return ctrlBuilder.Complete(r)
 }
@@ -0,0 +1,40 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadinessBackoff(t *testing.T) {
+	tests := []struct {
+		streak int
+		want   time.Duration
+	}{
+		{streak: 1, want: readinessRequeueBase},
+		{streak: 2, want: 2 * readinessRequeueBase},
+		{streak: 3, want: 4 * readinessRequeueBase},
+		{streak: 20, want: readinessRequeueMax},
+	}
+
+	for _, tt := range tests {
+		if got := readinessBackoff(tt.streak); got != tt.want {
+			t.Errorf("readinessBackoff(%d) = %s, want %s", tt.streak, got, tt.want)
+		}
+	}
+}
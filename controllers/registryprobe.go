@@ -0,0 +1,99 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+	"github.com/devfile/registry-operator/pkg/util"
+)
+
+// probeRegistryStatuses polls every registry in registries with the shared
+// util.Probe subsystem and folds the result against previousStatuses to
+// compute each entry's ConsecutiveFailures/LastTransitionTime, recording an
+// Event on recorder against obj whenever a registry's reachability flips.
+// It is shared between ClusterDevfileRegistriesListReconciler and
+// DevfileRegistriesListReconciler, which differ only in what they do with
+// the returned statuses/message afterwards (the cluster-scoped one has no
+// Ready condition to set; the namespaced one does).
+func probeRegistryStatuses(registries []registryv1alpha1.DevfileRegistryService, previousStatuses []registryv1alpha1.RegistryProbeStatus, recorder record.EventRecorder, obj runtime.Object) (statuses []registryv1alpha1.RegistryProbeStatus, message string, maxConsecutiveFailures int) {
+	previous := make(map[string]registryv1alpha1.RegistryProbeStatus, len(previousStatuses))
+	for _, s := range previousStatuses {
+		previous[s.URL] = s
+	}
+
+	now := metav1.Now()
+	statuses = make([]registryv1alpha1.RegistryProbeStatus, 0, len(registries))
+	message = allRegistriesReachable
+
+	for _, reg := range registries {
+		result := util.Probe(reg.URL, util.ProbeOptions{Method: http.MethodHead, Timeout: 5 * time.Second})
+		prev, hadPrev := previous[reg.URL]
+
+		status := registryv1alpha1.RegistryProbeStatus{
+			URL:                reg.URL,
+			Reachable:          result.Reachable,
+			StatusCode:         result.StatusCode,
+			LastProbeTime:      now,
+			LastTransitionTime: now,
+		}
+		if hadPrev {
+			if prev.Reachable == result.Reachable {
+				status.LastTransitionTime = prev.LastTransitionTime
+			}
+			if !result.Reachable {
+				status.ConsecutiveFailures = prev.ConsecutiveFailures + 1
+			}
+		} else if !result.Reachable {
+			status.ConsecutiveFailures = 1
+		}
+
+		if !hadPrev || prev.Reachable != result.Reachable {
+			recordProbeTransitionEvent(recorder, obj, reg.URL, result)
+		}
+
+		if !result.Reachable {
+			message = fmt.Sprintf(registryUnreachable, reg.URL)
+		}
+		if status.ConsecutiveFailures > maxConsecutiveFailures {
+			maxConsecutiveFailures = status.ConsecutiveFailures
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, message, maxConsecutiveFailures
+}
+
+func recordProbeTransitionEvent(recorder record.EventRecorder, obj runtime.Object, url string, result util.ProbeResult) {
+	if recorder == nil {
+		return
+	}
+	if result.Reachable {
+		recorder.Eventf(obj, corev1.EventTypeNormal, "RegistryReachable", "Devfile registry at %s is now reachable", url)
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeWarning, "RegistryUnreachable", "Devfile registry at %s became unreachable: %v", url, result.Err)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/client-go/tools/record"
+
+	registryv1alpha1 "github.com/devfile/registry-operator/api/v1alpha1"
+)
+
+func TestProbeRegistryStatuses(t *testing.T) {
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+
+	down := httptest.NewServer(nil)
+	down.Close() // closed immediately: connections to it are refused
+
+	registries := []registryv1alpha1.DevfileRegistryService{
+		{Name: "up", URL: up.URL},
+		{Name: "down", URL: down.URL},
+	}
+
+	recorder := record.NewFakeRecorder(10)
+	statuses, message, maxConsecutiveFailures := probeRegistryStatuses(registries, nil, recorder, &registryv1alpha1.DevfileRegistriesList{})
+
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+	byURL := make(map[string]registryv1alpha1.RegistryProbeStatus, len(statuses))
+	for _, s := range statuses {
+		byURL[s.URL] = s
+	}
+
+	if !byURL[up.URL].Reachable {
+		t.Errorf("expected %s to be reachable", up.URL)
+	}
+	if byURL[down.URL].Reachable {
+		t.Errorf("expected %s to be unreachable", down.URL)
+	}
+	if byURL[down.URL].ConsecutiveFailures != 1 {
+		t.Errorf("ConsecutiveFailures = %d, want 1 on first failure", byURL[down.URL].ConsecutiveFailures)
+	}
+	if maxConsecutiveFailures != 1 {
+		t.Errorf("maxConsecutiveFailures = %d, want 1", maxConsecutiveFailures)
+	}
+	if message == allRegistriesReachable {
+		t.Error("expected message to report the unreachable registry, not allRegistriesReachable")
+	}
+
+	drainEvents(t, recorder, 2)
+
+	// A second probe against the same (still down) registry should bump
+	// ConsecutiveFailures without emitting another transition event.
+	statuses, _, maxConsecutiveFailures = probeRegistryStatuses(registries, statuses, recorder, &registryv1alpha1.DevfileRegistriesList{})
+	byURL = make(map[string]registryv1alpha1.RegistryProbeStatus, len(statuses))
+	for _, s := range statuses {
+		byURL[s.URL] = s
+	}
+	if byURL[down.URL].ConsecutiveFailures != 2 {
+		t.Errorf("ConsecutiveFailures = %d, want 2 on second consecutive failure", byURL[down.URL].ConsecutiveFailures)
+	}
+	if maxConsecutiveFailures != 2 {
+		t.Errorf("maxConsecutiveFailures = %d, want 2", maxConsecutiveFailures)
+	}
+
+	select {
+	case e := <-recorder.Events:
+		t.Errorf("expected no further events for an unchanged registry, got %q", e)
+	default:
+	}
+}
+
+func TestProbeRegistryStatusesEmpty(t *testing.T) {
+	statuses, message, maxConsecutiveFailures := probeRegistryStatuses(nil, nil, nil, &registryv1alpha1.DevfileRegistriesList{})
+	if len(statuses) != 0 {
+		t.Errorf("got %d statuses, want 0", len(statuses))
+	}
+	if message != allRegistriesReachable {
+		t.Errorf("message = %q, want %q", message, allRegistriesReachable)
+	}
+	if maxConsecutiveFailures != 0 {
+		t.Errorf("maxConsecutiveFailures = %d, want 0", maxConsecutiveFailures)
+	}
+}
+
+func drainEvents(t *testing.T, recorder *record.FakeRecorder, want int) {
+	t.Helper()
+	for i := 0; i < want; i++ {
+		select {
+		case <-recorder.Events:
+		default:
+			t.Fatalf("expected %d events, only saw %d", want, i)
+		}
+	}
+}
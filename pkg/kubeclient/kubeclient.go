@@ -0,0 +1,126 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubeclient wraps the handful of mutating client-go calls the
+// controllers make (create/patch/get/delete) so that transient API errors -
+// resourceVersion conflicts chief among them - are retried with a shared
+// backoff policy instead of bubbling straight up into a full re-reconcile.
+package kubeclient
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Result classifies the outcome of a mutating call so callers can decide
+// whether to requeue immediately, requeue-after, or fail hard.
+type Result string
+
+const (
+	Created   Result = "Created"
+	Updated   Result = "Updated"
+	Unchanged Result = "Unchanged"
+	Conflict  Result = "Conflict"
+	Transient Result = "Transient"
+	Fatal     Result = "Fatal"
+)
+
+// Classify maps an error returned from the API server into a Result. A nil
+// error is never passed to Classify by this package's own helpers; it's
+// exported so callers composing their own retry loops can reuse the same
+// classification.
+func Classify(err error) Result {
+	switch {
+	case err == nil:
+		return Unchanged
+	case errors.IsConflict(err):
+		return Conflict
+	case errors.IsServerTimeout(err) || errors.IsTooManyRequests(err) || errors.IsTimeout(err):
+		return Transient
+	default:
+		return Fatal
+	}
+}
+
+func isRetryable(err error) bool {
+	r := Classify(err)
+	return r == Conflict || r == Transient
+}
+
+// CreateOrPatchWithRetry wraps controllerutil.CreateOrPatch, retrying on
+// conflicts with retry.DefaultBackoff and on IsServerTimeout/
+// IsTooManyRequests with the same bounded backoff, so a resourceVersion race
+// with another controller touching the same object doesn't turn into a
+// fatal reconcile error.
+func CreateOrPatchWithRetry(ctx context.Context, c client.Client, obj client.Object, mutate func() error) (Result, error) {
+	var opResult controllerutil.OperationResult
+	err := retry.OnError(retry.DefaultBackoff, isRetryable, func() error {
+		var err error
+		opResult, err = controllerutil.CreateOrPatch(ctx, c, obj, mutate)
+		return err
+	})
+	if err != nil {
+		return Classify(err), err
+	}
+
+	switch opResult {
+	case controllerutil.OperationResultCreated:
+		return Created, nil
+	case controllerutil.OperationResultNone:
+		return Unchanged, nil
+	default:
+		return Updated, nil
+	}
+}
+
+// GetWithRetry wraps client.Get, retrying transient server errors (but not
+// NotFound, which callers need to see immediately) with a bounded backoff.
+func GetWithRetry(ctx context.Context, c client.Client, key types.NamespacedName, obj client.Object) error {
+	return retry.OnError(retry.DefaultBackoff, isRetryable, func() error {
+		return c.Get(ctx, key, obj)
+	})
+}
+
+// DeleteWithRetry wraps client.Delete, retrying conflicts and transient
+// server errors. A NotFound error is swallowed, since the caller's goal -
+// the object being gone - is already satisfied.
+func DeleteWithRetry(ctx context.Context, c client.Client, obj client.Object) error {
+	err := retry.OnError(retry.DefaultBackoff, isRetryable, func() error {
+		return c.Delete(ctx, obj)
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// UpdateStatusWithRetry retries c.Status().Update on conflict by re-running
+// refresh (which should Get the latest object and re-apply the desired
+// status onto it) before each attempt, so a stale resourceVersion from a
+// concurrent status writer doesn't fail the whole reconcile.
+func UpdateStatusWithRetry(ctx context.Context, c client.Client, obj client.Object, refresh func() error) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := refresh(); err != nil {
+			return err
+		}
+		return c.Status().Update(ctx, obj)
+	})
+}
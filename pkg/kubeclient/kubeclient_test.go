@@ -0,0 +1,65 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeclient
+
+import (
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestClassify(t *testing.T) {
+	gr := schema.GroupResource{Group: "registry.devfile.io", Resource: "devfileregistries"}
+
+	tests := []struct {
+		name string
+		err  error
+		want Result
+	}{
+		{name: "nil error", err: nil, want: Unchanged},
+		{name: "conflict", err: apierrors.NewConflict(gr, "name", errors.New("conflict")), want: Conflict},
+		{name: "server timeout", err: apierrors.NewServerTimeout(gr, "get", 0), want: Transient},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("backoff", 0), want: Transient},
+		{name: "timeout", err: apierrors.NewTimeoutError("timed out", 0), want: Transient},
+		{name: "not found is fatal", err: apierrors.NewNotFound(gr, "name"), want: Fatal},
+		{name: "other error is fatal", err: errors.New("boom"), want: Fatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	gr := schema.GroupResource{Group: "registry.devfile.io", Resource: "devfileregistries"}
+
+	if !isRetryable(apierrors.NewConflict(gr, "name", errors.New("conflict"))) {
+		t.Error("expected a conflict error to be retryable")
+	}
+	if isRetryable(apierrors.NewNotFound(gr, "name")) {
+		t.Error("expected a NotFound error to not be retryable")
+	}
+	if isRetryable(nil) {
+		t.Error("expected a nil error to not be retryable (Classify(nil) == Unchanged)")
+	}
+}
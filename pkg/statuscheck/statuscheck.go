@@ -0,0 +1,222 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statuscheck inspects the resources owned by a DevfileRegistry and
+// reports whether each of them has reached its desired, ready state. The
+// per-resource checks mirror the readiness semantics used by Helm's kube
+// client (see `helm.sh/helm/v3/pkg/kube`) so that "ready" means the same
+// thing here as it does for a `helm upgrade --wait`.
+package statuscheck
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Condition types surfaced on DevfileRegistry.Status.Conditions.
+const (
+	ConditionDeploymentAvailable = "DeploymentAvailable"
+	ConditionStorageBound        = "StorageBound"
+	ConditionServiceReady        = "ServiceReady"
+	ConditionIngressAdmitted     = "IngressAdmitted"
+	ConditionRouteAdmitted       = "RouteAdmitted"
+	ConditionReady               = "Ready"
+)
+
+// Result is the outcome of checking a single owned resource.
+type Result struct {
+	Ready   bool
+	Reason  string
+	Message string
+}
+
+func ready(reason string) Result {
+	return Result{Ready: true, Reason: reason, Message: ""}
+}
+
+func notReady(reason, message string) Result {
+	return Result{Ready: false, Reason: reason, Message: message}
+}
+
+// CheckDeployment reports whether dep has rolled out successfully: the
+// deployment controller has observed the latest spec, the desired replica
+// count has been updated and is available, and the newest ReplicaSet it
+// owns has become the active one.
+func CheckDeployment(ctx context.Context, c client.Client, dep *appsv1.Deployment) (Result, error) {
+	if dep.Status.ObservedGeneration < dep.Generation {
+		return notReady("ObservedGenerationOutdated", "waiting for the deployment controller to observe the latest spec"), nil
+	}
+
+	var desired int32 = 1
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+
+	if dep.Status.UpdatedReplicas < desired {
+		return notReady("RolloutInProgress", fmt.Sprintf("%d out of %d new replicas updated", dep.Status.UpdatedReplicas, desired)), nil
+	}
+	if dep.Status.AvailableReplicas < desired {
+		return notReady("RolloutInProgress", fmt.Sprintf("%d of %d updated replicas available", dep.Status.AvailableReplicas, desired)), nil
+	}
+
+	rs, err := newestReplicaSetFor(ctx, c, dep)
+	if err != nil {
+		return Result{}, err
+	}
+	if rs == nil {
+		return notReady("ReplicaSetMissing", "no ReplicaSet owned by this deployment was found yet"), nil
+	}
+
+	for _, cond := range dep.Status.Conditions {
+		if cond.Type != appsv1.DeploymentProgressing {
+			continue
+		}
+		if cond.Status == corev1.ConditionTrue && cond.Reason == "NewReplicaSetAvailable" {
+			return ready("DeploymentAvailable"), nil
+		}
+		return notReady(cond.Reason, cond.Message), nil
+	}
+
+	return notReady("ProgressingConditionMissing", "deployment has not reported a Progressing condition yet"), nil
+}
+
+// newestReplicaSetFor returns the most recently created ReplicaSet owned by
+// dep, or nil if none exist yet.
+func newestReplicaSetFor(ctx context.Context, c client.Client, dep *appsv1.Deployment) (*appsv1.ReplicaSet, error) {
+	rsList := &appsv1.ReplicaSetList{}
+	if err := c.List(ctx, rsList, client.InNamespace(dep.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var owned []appsv1.ReplicaSet
+	for _, rs := range rsList.Items {
+		for _, ref := range rs.OwnerReferences {
+			if ref.UID == dep.UID {
+				owned = append(owned, rs)
+				break
+			}
+		}
+	}
+	if len(owned) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(owned, func(i, j int) bool {
+		return owned[i].CreationTimestamp.After(owned[j].CreationTimestamp.Time)
+	})
+	return &owned[0], nil
+}
+
+// CheckPVC reports whether pvc has been bound to a volume.
+func CheckPVC(pvc *corev1.PersistentVolumeClaim) Result {
+	if pvc.Status.Phase == corev1.ClaimBound {
+		return ready("StorageBound")
+	}
+	return notReady("StorageNotBound", fmt.Sprintf("persistentvolumeclaim is in phase %q", pvc.Status.Phase))
+}
+
+// CheckService reports whether svc is ready to route traffic. Headless and
+// ExternalName services are always considered ready, since they have no
+// allocated ClusterIP or LoadBalancer to wait for.
+func CheckService(svc *corev1.Service) Result {
+	if svc.Spec.Type == corev1.ServiceTypeExternalName || svc.Spec.ClusterIP == corev1.ClusterIPNone {
+		return ready("ServiceReady")
+	}
+	if svc.Spec.ClusterIP == "" {
+		return notReady("ClusterIPNotAssigned", "waiting for a ClusterIP to be allocated")
+	}
+	if svc.Spec.Type == corev1.ServiceTypeLoadBalancer && len(svc.Status.LoadBalancer.Ingress) == 0 {
+		return notReady("LoadBalancerPending", "waiting for the load balancer to be provisioned")
+	}
+	return ready("ServiceReady")
+}
+
+// CheckIngress reports whether ing has been admitted by an ingress
+// controller, i.e. at least one load balancer ingress point has been
+// assigned.
+func CheckIngress(ing *networkingv1.Ingress) Result {
+	if len(ing.Status.LoadBalancer.Ingress) == 0 {
+		return notReady("IngressNotAdmitted", "waiting for an ingress controller to assign a load balancer address")
+	}
+	return ready("IngressAdmitted")
+}
+
+// CheckRoute reports whether route has an Admitted=True condition on any of
+// its ingress entries.
+func CheckRoute(route *routev1.Route) Result {
+	for _, ingress := range route.Status.Ingress {
+		for _, cond := range ingress.Conditions {
+			if cond.Type == routev1.RouteAdmitted && cond.Status == corev1.ConditionTrue {
+				return ready("RouteAdmitted")
+			}
+		}
+	}
+	return notReady("RouteNotAdmitted", "waiting for the route to be admitted by a router")
+}
+
+// Aggregate rolls a set of per-resource results, keyed by condition type,
+// into a list of metav1.Conditions plus an overall Ready condition.
+func Aggregate(generation int64, results map[string]Result) []metav1.Condition {
+	conditions := make([]metav1.Condition, 0, len(results)+1)
+
+	allReady := true
+	var types []string
+	for t := range results {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		r := results[t]
+		status := metav1.ConditionTrue
+		if !r.Ready {
+			status = metav1.ConditionFalse
+			allReady = false
+		}
+		conditions = append(conditions, metav1.Condition{
+			Type:               t,
+			Status:             status,
+			ObservedGeneration: generation,
+			Reason:             r.Reason,
+			Message:            r.Message,
+		})
+	}
+
+	readyCondition := metav1.Condition{
+		Type:               ConditionReady,
+		ObservedGeneration: generation,
+		Reason:             "AllResourcesReady",
+		Message:            "all owned resources are ready",
+	}
+	if allReady {
+		readyCondition.Status = metav1.ConditionTrue
+	} else {
+		readyCondition.Status = metav1.ConditionFalse
+		readyCondition.Reason = "ResourcesNotReady"
+		readyCondition.Message = "one or more owned resources are not yet ready"
+	}
+	conditions = append(conditions, readyCondition)
+
+	return conditions
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statuscheck
+
+import (
+	"context"
+	"testing"
+
+	routev1 "github.com/openshift/api/route/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckPVC(t *testing.T) {
+	tests := []struct {
+		name  string
+		phase corev1.PersistentVolumeClaimPhase
+		ready bool
+	}{
+		{name: "bound", phase: corev1.ClaimBound, ready: true},
+		{name: "pending", phase: corev1.ClaimPending, ready: false},
+		{name: "lost", phase: corev1.ClaimLost, ready: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := &corev1.PersistentVolumeClaim{Status: corev1.PersistentVolumeClaimStatus{Phase: tt.phase}}
+			if got := CheckPVC(pvc); got.Ready != tt.ready {
+				t.Errorf("CheckPVC() ready = %v, want %v", got.Ready, tt.ready)
+			}
+		})
+	}
+}
+
+func TestCheckService(t *testing.T) {
+	tests := []struct {
+		name  string
+		svc   *corev1.Service
+		ready bool
+	}{
+		{
+			name:  "external name service is always ready",
+			svc:   &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeExternalName}},
+			ready: true,
+		},
+		{
+			name:  "headless service is always ready",
+			svc:   &corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: corev1.ClusterIPNone}},
+			ready: true,
+		},
+		{
+			name:  "clusterIP not yet assigned",
+			svc:   &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP}},
+			ready: false,
+		},
+		{
+			name:  "clusterIP service ready",
+			svc:   &corev1.Service{Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP, ClusterIP: "10.0.0.1"}},
+			ready: true,
+		},
+		{
+			name: "load balancer pending",
+			svc: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+			},
+			ready: false,
+		},
+		{
+			name: "load balancer provisioned",
+			svc: &corev1.Service{
+				Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.1"},
+				Status: corev1.ServiceStatus{
+					LoadBalancer: corev1.LoadBalancerStatus{Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+				},
+			},
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckService(tt.svc); got.Ready != tt.ready {
+				t.Errorf("CheckService() ready = %v, want %v", got.Ready, tt.ready)
+			}
+		})
+	}
+}
+
+func TestCheckIngress(t *testing.T) {
+	tests := []struct {
+		name  string
+		ing   *networkingv1.Ingress
+		ready bool
+	}{
+		{name: "not admitted", ing: &networkingv1.Ingress{}, ready: false},
+		{
+			name: "admitted",
+			ing: &networkingv1.Ingress{
+				Status: networkingv1.IngressStatus{
+					LoadBalancer: networkingv1.IngressLoadBalancerStatus{
+						Ingress: []networkingv1.IngressLoadBalancerIngress{{IP: "1.2.3.4"}},
+					},
+				},
+			},
+			ready: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckIngress(tt.ing); got.Ready != tt.ready {
+				t.Errorf("CheckIngress() ready = %v, want %v", got.Ready, tt.ready)
+			}
+		})
+	}
+}
+
+func TestCheckRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		route *routev1.Route
+		ready bool
+	}{
+		{name: "no ingress entries", route: &routev1.Route{}, ready: false},
+		{
+			name: "admitted",
+			route: &routev1.Route{
+				Status: routev1.RouteStatus{
+					Ingress: []routev1.RouteIngress{{
+						Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+					}},
+				},
+			},
+			ready: true,
+		},
+		{
+			name: "rejected",
+			route: &routev1.Route{
+				Status: routev1.RouteStatus{
+					Ingress: []routev1.RouteIngress{{
+						Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionFalse}},
+					}},
+				},
+			},
+			ready: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckRoute(tt.route); got.Ready != tt.ready {
+				t.Errorf("CheckRoute() ready = %v, want %v", got.Ready, tt.ready)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Run("all ready", func(t *testing.T) {
+		conditions := Aggregate(3, map[string]Result{
+			ConditionDeploymentAvailable: {Ready: true, Reason: "DeploymentAvailable"},
+			ConditionServiceReady:        {Ready: true, Reason: "ServiceReady"},
+		})
+
+		ready := findCondition(conditions, ConditionReady)
+		if ready == nil {
+			t.Fatal("expected a Ready condition")
+		}
+		if ready.Status != metav1.ConditionTrue {
+			t.Errorf("Ready condition status = %v, want True", ready.Status)
+		}
+		if ready.ObservedGeneration != 3 {
+			t.Errorf("Ready condition ObservedGeneration = %d, want 3", ready.ObservedGeneration)
+		}
+	})
+
+	t.Run("one not ready", func(t *testing.T) {
+		conditions := Aggregate(1, map[string]Result{
+			ConditionDeploymentAvailable: {Ready: true, Reason: "DeploymentAvailable"},
+			ConditionServiceReady:        {Ready: false, Reason: "ClusterIPNotAssigned", Message: "waiting"},
+		})
+
+		ready := findCondition(conditions, ConditionReady)
+		if ready == nil {
+			t.Fatal("expected a Ready condition")
+		}
+		if ready.Status != metav1.ConditionFalse {
+			t.Errorf("Ready condition status = %v, want False", ready.Status)
+		}
+
+		svc := findCondition(conditions, ConditionServiceReady)
+		if svc == nil || svc.Status != metav1.ConditionFalse {
+			t.Errorf("ServiceReady condition = %+v, want Status=False", svc)
+		}
+	})
+}
+
+func findCondition(conditions []metav1.Condition, condType string) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func TestCheckDeployment(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := appsv1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "devfile-registry", Namespace: "default", UID: types.UID("dep-uid"), Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(1)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+			Conditions: []appsv1.DeploymentCondition{{
+				Type:   appsv1.DeploymentProgressing,
+				Status: corev1.ConditionTrue,
+				Reason: "NewReplicaSetAvailable",
+			}},
+		},
+	}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "devfile-registry-abc123",
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{UID: dep.UID}},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dep, rs).Build()
+
+	result, err := CheckDeployment(context.Background(), c, dep)
+	if err != nil {
+		t.Fatalf("CheckDeployment() error = %v", err)
+	}
+	if !result.Ready {
+		t.Errorf("CheckDeployment() ready = false, want true (result=%+v)", result)
+	}
+}
+
+func int32Ptr(i int32) *int32 { return &i }
@@ -0,0 +1,88 @@
+/*
+Copyright 2020-2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// ProbeOptions configures a single reachability probe.
+type ProbeOptions struct {
+	// Method is the HTTP method to use, GET or HEAD. Defaults to GET when empty.
+	Method string
+	// Timeout bounds how long the probe waits for a response.
+	Timeout time.Duration
+	// SkipTLSVerify disables TLS certificate verification, for registries
+	// that haven't had a signed certificate installed yet.
+	SkipTLSVerify bool
+}
+
+// ProbeResult is the outcome of a single Probe call.
+type ProbeResult struct {
+	Reachable  bool
+	StatusCode int
+	Err        error
+}
+
+// Probe performs a single HTTP reachability check against url according to
+// opts. It is the shared primitive behind both the DevfileRegistry readiness
+// check and the *DevfileRegistriesList periodic reachability poll, so both
+// get the same timeout, method, and TLS-skip semantics.
+func Probe(url string, opts ProbeOptions) ProbeResult {
+	method := opts.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	client := &http.Client{Timeout: opts.Timeout}
+	if opts.SkipTLSVerify {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // nolint:gosec // opt-in, used only before a signed cert is installed
+		}
+	}
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ProbeResult{Err: err}
+	}
+	defer resp.Body.Close()
+
+	return ProbeResult{
+		Reachable:  resp.StatusCode < http.StatusInternalServerError,
+		StatusCode: resp.StatusCode,
+	}
+}
+
+// Backoff returns the delay before the (1-indexed) nth consecutive retry of
+// a failing operation, doubling from base up to max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}